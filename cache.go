@@ -0,0 +1,224 @@
+package himawari
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrCacheMiss is returned by Cache.Get when a chunk is not present in the cache.
+var ErrCacheMiss = errors.New("himawari: cache miss")
+
+// Cache stores decoded chunk images keyed by (time, level, x, y) so that callers
+// fetching the same or adjacent timestamps don't have to re-download chunks
+// that haven't changed.
+type Cache interface {
+	// Get returns the cached chunk, or ErrCacheMiss if it isn't cached.
+	Get(t time.Time, level, x, y int) (image.Image, error)
+	// Put stores a chunk in the cache.
+	Put(t time.Time, level, x, y int, img image.Image) error
+}
+
+type chunkKey struct {
+	t           int64
+	level, x, y int
+}
+
+func newChunkKey(t time.Time, level, x, y int) chunkKey {
+	return chunkKey{t: t.UTC().Unix(), level: level, x: x, y: y}
+}
+
+// MemoryCache is a Cache backed by an in-memory map. It is safe for concurrent use.
+type MemoryCache struct {
+	mu sync.RWMutex
+	m  map[chunkKey]image.Image
+}
+
+// NewMemoryCache creates an empty in-memory Cache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{m: make(map[chunkKey]image.Image)}
+}
+
+func (c *MemoryCache) Get(t time.Time, level, x, y int) (image.Image, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	img, ok := c.m[newChunkKey(t, level, x, y)]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	return img, nil
+}
+
+func (c *MemoryCache) Put(t time.Time, level, x, y int, img image.Image) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.m[newChunkKey(t, level, x, y)] = img
+	return nil
+}
+
+// DiskCache is a Cache that stores chunks as PNG files under Dir.
+type DiskCache struct {
+	Dir string
+}
+
+// NewDiskCache creates a Cache that persists chunks as PNG files under dir.
+// The directory is created lazily on the first Put.
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{Dir: dir}
+}
+
+func (c *DiskCache) path(t time.Time, level, x, y int) string {
+	name := fmt.Sprintf("%d_%s_%d_%d.png", level, t.UTC().Format("20060102150405"), x, y)
+	return filepath.Join(c.Dir, name)
+}
+
+func (c *DiskCache) Get(t time.Time, level, x, y int) (image.Image, error) {
+	f, err := os.Open(c.path(t, level, x, y))
+	if os.IsNotExist(err) {
+		return nil, ErrCacheMiss
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func (c *DiskCache) Put(t time.Time, level, x, y int, img image.Image) error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(c.path(t, level, x, y))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return png.Encode(f, img)
+}
+
+// cachedChunk returns the chunk at (t, level, x, y), serving it from cache when
+// possible and storing freshly downloaded chunks back into the cache.
+func (c *Client) cachedChunk(ctx context.Context, cache Cache, t time.Time, level, x, y int) (image.Image, error) {
+	if img, err := cache.Get(t, level, x, y); err == nil {
+		return img, nil
+	} else if err != ErrCacheMiss {
+		return nil, err
+	}
+	img, err := c.Chunk(ctx, t, level, x, y)
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.Put(t, level, x, y, img); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// CachedImage behaves like Image, but serves chunks from cache when they were
+// already downloaded for the same timestamp and level, only fetching chunks
+// that are missing. Like Image, it fetches missing chunks concurrently using
+// up to c.workers() goroutines, honors ctx cancellation, and goes through c's
+// rate limiter and retry policy.
+func (c *Client) CachedImage(ctx context.Context, cache Cache, t time.Time, level int) (image.Image, error) {
+	if level <= 0 {
+		level = DefaultLevel
+	}
+	if level == 1 {
+		return c.cachedChunk(ctx, cache, t, level, 0, 0)
+	}
+	return c.assemble(ctx, level, func(ctx context.Context, x, y int) (image.Image, error) {
+		return c.cachedChunk(ctx, cache, t, level, x, y)
+	})
+}
+
+// CachedImage behaves like Image, but serves chunks from cache when they were
+// already downloaded for the same timestamp and level, only fetching chunks
+// that are missing.
+func CachedImage(ctx context.Context, cache Cache, t time.Time, level int) (image.Image, error) {
+	return DefaultClient.CachedImage(ctx, cache, t, level)
+}
+
+// Watcher polls for new Himawari snapshots and emits the assembled image each
+// time a new timestamp becomes available, reusing a Cache so that chunks
+// already seen are not re-downloaded.
+type Watcher struct {
+	Cache Cache
+	// Client is used to poll for and fetch snapshots. Defaults to DefaultClient,
+	// so set it to apply a rate limiter or custom retry policy to Watch.
+	Client *Client
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewWatcher creates a Watcher that stores downloaded chunks in cache.
+func NewWatcher(cache Cache) *Watcher {
+	return &Watcher{Cache: cache}
+}
+
+func (w *Watcher) client() *Client {
+	if w.Client != nil {
+		return w.Client
+	}
+	return DefaultClient
+}
+
+// Watch polls Latest every interval and, whenever a new timestamp is published,
+// downloads the tiles that aren't already cached and sends the assembled image
+// on the returned channel as a Frame. A failed poll (Latest or the image fetch
+// erroring) is sent as a Frame with Err set rather than discarded, so a
+// long-running consumer can tell "no new snapshot yet" from "polling is
+// failing". The channel is closed when ctx is done.
+func (w *Watcher) Watch(ctx context.Context, level int, interval time.Duration) (<-chan Frame, error) {
+	out := make(chan Frame)
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			w.poll(ctx, level, out)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (w *Watcher) poll(ctx context.Context, level int, out chan<- Frame) {
+	client := w.client()
+	latest, err := client.Latest(ctx)
+	if err != nil {
+		w.send(ctx, out, Frame{Err: err})
+		return
+	}
+	w.mu.Lock()
+	isNew := !latest.Equal(w.last)
+	if isNew {
+		w.last = latest
+	}
+	w.mu.Unlock()
+	if !isNew {
+		return
+	}
+	img, err := client.CachedImage(ctx, w.Cache, latest, level)
+	if err != nil {
+		w.send(ctx, out, Frame{Time: latest, Err: err})
+		return
+	}
+	w.send(ctx, out, Frame{Time: latest, Image: img})
+}
+
+func (w *Watcher) send(ctx context.Context, out chan<- Frame, f Frame) {
+	select {
+	case out <- f:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,58 @@
+package himawari
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+)
+
+func testImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+	return img
+}
+
+func testCacheRoundTrip(t *testing.T, cache Cache) {
+	t.Helper()
+	tm := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+
+	if _, err := cache.Get(tm, 4, 1, 2); err != ErrCacheMiss {
+		t.Fatalf("Get on empty cache: got err = %v, want ErrCacheMiss", err)
+	}
+
+	want := testImage()
+	if err := cache.Put(tm, 4, 1, 2, want); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, err := cache.Get(tm, 4, 1, 2)
+	if err != nil {
+		t.Fatalf("Get after Put: %v", err)
+	}
+	wb, gb := want.Bounds(), got.Bounds()
+	if wb != gb {
+		t.Fatalf("bounds mismatch: got %v, want %v", gb, wb)
+	}
+	wr, wg, wbl, wa := want.At(0, 0).RGBA()
+	gr, gg, gbl, ga := got.At(0, 0).RGBA()
+	if wr != gr || wg != gg || wbl != gbl || wa != ga {
+		t.Fatalf("pixel mismatch: got %v, want %v", got.At(0, 0), want.At(0, 0))
+	}
+
+	// A different key (level, x, y or time) must still miss.
+	if _, err := cache.Get(tm, 4, 1, 3); err != ErrCacheMiss {
+		t.Fatalf("Get with different x: got err = %v, want ErrCacheMiss", err)
+	}
+	if _, err := cache.Get(tm.Add(time.Minute), 4, 1, 2); err != ErrCacheMiss {
+		t.Fatalf("Get with different time: got err = %v, want ErrCacheMiss", err)
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	testCacheRoundTrip(t, NewMemoryCache())
+}
+
+func TestDiskCache(t *testing.T) {
+	testCacheRoundTrip(t, NewDiskCache(t.TempDir()))
+}
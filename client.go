@@ -0,0 +1,286 @@
+package himawari
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultClient is the Client used by the package-level Latest, Chunk, Image
+// and LatestImage functions.
+var DefaultClient = &Client{}
+
+// Client fetches Himawari-8 data over HTTP, with support for cancellation,
+// rate limiting and retries. The zero value is a usable client with
+// conservative defaults.
+type Client struct {
+	// HTTPClient is used to make requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Limiter, if set, bounds the rate of outgoing requests. This matters at
+	// high zoom levels (16, 20 -> 256/400 chunks per image) to avoid getting
+	// banned by nict.go.jp.
+	Limiter *rate.Limiter
+	// MaxRetries is the number of additional attempts made after a request
+	// fails with a transient error (a network error or a 5xx status).
+	// Defaults to 3.
+	MaxRetries int
+	// RetryBaseDelay is the delay before the first retry; it doubles on each
+	// subsequent attempt. Defaults to 500ms.
+	RetryBaseDelay time.Duration
+	// Workers is the number of goroutines Image uses to fetch chunks
+	// concurrently. Defaults to the package-level Workers variable.
+	Workers int
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func (c *Client) maxRetries() int {
+	if c.MaxRetries > 0 {
+		return c.MaxRetries
+	}
+	return 3
+}
+
+func (c *Client) retryBaseDelay() time.Duration {
+	if c.RetryBaseDelay > 0 {
+		return c.RetryBaseDelay
+	}
+	return 500 * time.Millisecond
+}
+
+func (c *Client) workers() int {
+	if c.Workers > 0 {
+		return c.Workers
+	}
+	return Workers
+}
+
+// get performs a GET request against url, honoring ctx cancellation and the
+// client's rate limiter, and retrying transient errors (network errors and
+// 5xx responses) with exponential backoff.
+func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	delay := c.retryBaseDelay()
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries(); attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if c.Limiter != nil {
+			if err := c.Limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.httpClient().Do(req)
+		switch {
+		case err != nil:
+			lastErr = err
+		case resp.StatusCode >= 500:
+			resp.Body.Close()
+			lastErr = fmt.Errorf("himawari: %s: %s", url, resp.Status)
+		case resp.StatusCode != http.StatusOK:
+			resp.Body.Close()
+			return nil, fmt.Errorf("himawari: %s: %s", url, resp.Status)
+		default:
+			return resp, nil
+		}
+		if attempt == c.maxRetries() {
+			break
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		delay *= 2
+	}
+	return nil, lastErr
+}
+
+// head performs a HEAD request against url, honoring ctx cancellation and the
+// client's rate limiter, reporting whether the server returned 200 OK.
+func (c *Client) head(ctx context.Context, url string) (bool, error) {
+	if c.Limiter != nil {
+		if err := c.Limiter.Wait(ctx); err != nil {
+			return false, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// Latest returns a timestamp of latest image available.
+func (c *Client) Latest(ctx context.Context) (time.Time, error) {
+	resp, err := c.get(ctx, "http://himawari8-dl.nict.go.jp/himawari8/img/D531106/latest.json")
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer resp.Body.Close()
+	var r struct {
+		Date string `json:"date"`
+		//File string `json:"file"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return time.Time{}, err
+	}
+	latest, err := time.Parse("2006-01-02 15:04:05", r.Date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return latest, nil
+}
+
+// Chunk returns a decoded chunk image at specific time.
+func (c *Client) Chunk(ctx context.Context, t time.Time, level, x, y int) (image.Image, error) {
+	resp, err := c.get(ctx, ChunkUrl(t, level, Width, x, y))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return png.Decode(resp.Body)
+}
+
+// Image loads a whole satellite image for a given time and zoom level.
+//
+// If level = 0, default zoom level will be used. Image fails fast and drains
+// outstanding work as soon as ctx is done or a chunk fetch returns an error.
+func (c *Client) Image(ctx context.Context, t time.Time, level int) (image.Image, error) {
+	if level <= 0 {
+		level = DefaultLevel
+	}
+	if level == 1 {
+		return c.Chunk(ctx, t, level, 0, 0)
+	}
+	return c.assemble(ctx, level, func(ctx context.Context, x, y int) (image.Image, error) {
+		return c.Chunk(ctx, t, level, x, y)
+	})
+}
+
+// assemble builds a level*Width x level*Height canvas by calling fetch for
+// every tile, using up to c.workers() goroutines. It fails fast and drains
+// outstanding work as soon as ctx is done or fetch returns an error.
+func (c *Client) assemble(ctx context.Context, level int, fetch func(ctx context.Context, x, y int) (image.Image, error)) (image.Image, error) {
+	workers := c.workers()
+	if workers <= 0 {
+		workers = 1
+	} else if total := level * level; workers > total {
+		workers = total
+	}
+	canvas := image.NewRGBA(image.Rect(0, 0, level*Width, level*Height))
+
+	if workers == 1 {
+		for y := 0; y < level; y++ {
+			for x := 0; x < level; x++ {
+				if err := ctx.Err(); err != nil {
+					return canvas, err
+				}
+				img, err := fetch(ctx, x, y)
+				if err != nil {
+					return canvas, err
+				}
+				drawChunk(canvas, x, y, img)
+			}
+		}
+		return canvas, nil
+	}
+
+	type job struct{ x, y int }
+	jobs := make(chan job)
+	errc := make(chan error, 1)
+
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-workCtx.Done():
+					return
+				case j, ok := <-jobs:
+					if !ok {
+						return
+					}
+					img, err := fetch(workCtx, j.x, j.y)
+					if err != nil {
+						select {
+						case errc <- err:
+						default:
+						}
+						cancel()
+						return
+					}
+					drawChunk(canvas, j.x, j.y, img)
+				}
+			}
+		}()
+	}
+
+feed:
+	for y := 0; y < level; y++ {
+		for x := 0; x < level; x++ {
+			select {
+			case jobs <- job{x, y}:
+			case <-workCtx.Done():
+				break feed
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	select {
+	case err := <-errc:
+		return canvas, err
+	default:
+	}
+	if err := ctx.Err(); err != nil {
+		return canvas, err
+	}
+	return canvas, nil
+}
+
+// LatestImage loads most recent satellite image for a given zoom level.
+//
+// If level = 0, default zoom level will be used.
+//
+// offsetTime parameter can be set to true to correct time to local time zone.
+func (c *Client) LatestImage(ctx context.Context, level int, offsetTime bool) (image.Image, error) {
+	if level <= 0 {
+		level = DefaultLevel
+	}
+	t, err := c.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if offsetTime {
+		t = TimeWithOffset(t)
+	}
+	return c.Image(ctx, t, level)
+}
@@ -0,0 +1,137 @@
+package himawari
+
+import (
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// rewriteTransport redirects every request to ts, regardless of the host in
+// the request URL, so Client (which builds URLs against the real
+// nict.go.jp/himawari8-dl.nict.go.jp hosts) can be exercised against a local
+// httptest.Server without touching the network.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	target, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Client{
+		HTTPClient:     &http.Client{Transport: &rewriteTransport{target: target}},
+		RetryBaseDelay: time.Millisecond,
+	}
+}
+
+func pngChunk(t *testing.T) []byte {
+	t.Helper()
+	var buf countingWriter
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.b
+}
+
+type countingWriter struct{ b []byte }
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.b = append(w.b, p...)
+	return len(p), nil
+}
+
+func TestClientLatest(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"date": "2026-01-02 03:04:05"}`))
+	}))
+	got, err := c.Latest(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestClientChunk(t *testing.T) {
+	body := pngChunk(t)
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	img, err := c.Chunk(context.Background(), time.Now(), DefaultLevel, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != Width || b.Dy() != Height {
+		t.Fatalf("got bounds %v, want %dx%d", b, Width, Height)
+	}
+}
+
+func TestClientGetRetriesOn5xx(t *testing.T) {
+	body := pngChunk(t)
+	var attempts int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write(body)
+	}))
+	if _, err := c.Chunk(context.Background(), time.Now(), DefaultLevel, 0, 0); err != nil {
+		t.Fatalf("Chunk: %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("got %d attempts, want 3 (2 failures + 1 success)", got)
+	}
+}
+
+func TestClientImageAssemblesAllChunks(t *testing.T) {
+	body := pngChunk(t)
+	const level = 2
+	var requests int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write(body)
+	}))
+	img, err := c.Image(context.Background(), time.Now(), level)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != level*Width || b.Dy() != level*Height {
+		t.Fatalf("got bounds %v, want %dx%d", b, level*Width, level*Height)
+	}
+	if got, want := atomic.LoadInt32(&requests), int32(level*level); got != want {
+		t.Fatalf("got %d chunk requests, want %d", got, want)
+	}
+}
+
+func TestClientImageCanceledContext(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(pngChunk(t))
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.Image(ctx, time.Now(), 4); err == nil {
+		t.Fatal("expected an error for an already-canceled context")
+	}
+}
@@ -0,0 +1,120 @@
+package himawari
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"time"
+
+	"golang.org/x/image/draw"
+)
+
+// Format identifies an output image encoding for Encode.
+type Format int
+
+const (
+	// FormatPNG encodes as PNG (the default).
+	FormatPNG Format = iota
+	// FormatJPEG encodes as JPEG.
+	FormatJPEG
+	// FormatWebP encodes as WebP, via WebPEncoder.
+	FormatWebP
+)
+
+// EncodeOptions configures Encode.
+type EncodeOptions struct {
+	Format Format
+	// Quality is used by FormatJPEG and FormatWebP, in the range [1, 100].
+	// Zero means a reasonable default.
+	Quality int
+}
+
+// WebPEncoder encodes img as WebP to w at the given quality. himawari has no
+// built-in WebP encoder (there's no pure-Go one in golang.org/x/image), so
+// callers that want FormatWebP must set this, e.g. to a wrapper around
+// github.com/chai2010/webp.Encode.
+var WebPEncoder func(w io.Writer, img image.Image, quality int) error
+
+// Encode writes img to w using the format and quality described by opts.
+func Encode(w io.Writer, img image.Image, opts EncodeOptions) error {
+	switch opts.Format {
+	case FormatPNG:
+		return png.Encode(w, img)
+	case FormatJPEG:
+		q := opts.Quality
+		if q <= 0 {
+			q = 90
+		}
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: q})
+	case FormatWebP:
+		if WebPEncoder == nil {
+			return fmt.Errorf("himawari: FormatWebP requires WebPEncoder to be set")
+		}
+		q := opts.Quality
+		if q <= 0 {
+			q = 90
+		}
+		return WebPEncoder(w, img, q)
+	default:
+		return fmt.Errorf("himawari: unknown format %v", opts.Format)
+	}
+}
+
+// RenderOptions configures RenderImage.
+type RenderOptions struct {
+	// Client fetches the image. Defaults to DefaultClient; set it to apply a
+	// rate limiter, custom retry policy or *http.Client, which matters at the
+	// high zoom levels a wallpaper-sized render typically asks for.
+	Client *Client
+	// Level is the zoom level to fetch. If zero, DefaultLevel is used.
+	Level int
+	// Width and Height are the desired output dimensions. If either is zero,
+	// the image is returned at its native level*Width x level*Height size.
+	Width, Height int
+	// Scaler selects the resampling algorithm used when Width/Height differ
+	// from the native size. Defaults to draw.CatmullRom.
+	Scaler draw.Interpolator
+	// Transparent makes the black space surrounding Earth's disc transparent,
+	// so the image can be composited over a custom background.
+	Transparent bool
+}
+
+// RenderImage fetches the image for t like Image does, then resamples it to
+// opts.Width x opts.Height using high-quality interpolation, so callers that
+// need a specific output size (e.g. a 3840x2160 wallpaper) don't have to
+// allocate and downsample the full native canvas themselves.
+func RenderImage(ctx context.Context, t time.Time, opts RenderOptions) (image.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	level := opts.Level
+	if level <= 0 {
+		level = DefaultLevel
+	}
+	client := opts.Client
+	if client == nil {
+		client = DefaultClient
+	}
+	img, err := client.Image(ctx, t, level)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Transparent {
+		rgba := asRGBA(img)
+		makeTransparent(rgba)
+		img = rgba
+	}
+	if opts.Width <= 0 || opts.Height <= 0 {
+		return img, nil
+	}
+	scaler := opts.Scaler
+	if scaler == nil {
+		scaler = draw.CatmullRom
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+	scaler.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Over, nil)
+	return dst, nil
+}
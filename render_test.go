@@ -0,0 +1,85 @@
+package himawari
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEncodeFormats(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+
+	var pngBuf bytes.Buffer
+	if err := Encode(&pngBuf, img, EncodeOptions{Format: FormatPNG}); err != nil {
+		t.Fatalf("Encode PNG: %v", err)
+	}
+	if _, err := png.Decode(&pngBuf); err != nil {
+		t.Fatalf("decode PNG output: %v", err)
+	}
+
+	var jpegBuf bytes.Buffer
+	if err := Encode(&jpegBuf, img, EncodeOptions{Format: FormatJPEG, Quality: 80}); err != nil {
+		t.Fatalf("Encode JPEG: %v", err)
+	}
+	if _, err := jpeg.Decode(&jpegBuf); err != nil {
+		t.Fatalf("decode JPEG output: %v", err)
+	}
+}
+
+func TestEncodeWebPRequiresEncoder(t *testing.T) {
+	old := WebPEncoder
+	WebPEncoder = nil
+	defer func() { WebPEncoder = old }()
+
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	if err := Encode(new(bytes.Buffer), img, EncodeOptions{Format: FormatWebP}); err == nil {
+		t.Fatal("expected an error when WebPEncoder is unset")
+	}
+}
+
+func TestRenderImageUsesProvidedClient(t *testing.T) {
+	body := pngChunk(t)
+	var requests int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write(body)
+	}))
+
+	img, err := RenderImage(context.Background(), time.Now(), RenderOptions{Client: c, Level: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != Width || b.Dy() != Height {
+		t.Fatalf("got bounds %v, want %dx%d", b, Width, Height)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("got %d requests against the provided Client, want 1 (DefaultClient would hit the real host and fail)", got)
+	}
+}
+
+func TestRenderImageResamplesToRequestedSize(t *testing.T) {
+	body := pngChunk(t)
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+
+	const wantW, wantH = 100, 80
+	img, err := RenderImage(context.Background(), time.Now(), RenderOptions{
+		Client: c,
+		Level:  1,
+		Width:  wantW,
+		Height: wantH,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != wantW || b.Dy() != wantH {
+		t.Fatalf("got bounds %v, want %dx%d", b, wantW, wantH)
+	}
+}
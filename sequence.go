@@ -0,0 +1,175 @@
+package himawari
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+)
+
+// Frame is a single timestamped image produced by Sequence.
+type Frame struct {
+	Time  time.Time
+	Image image.Image
+	// Err is set, and Image is nil, when the timestamp couldn't be fetched
+	// (e.g. Himawari hadn't published it yet). Sequence continues to the next
+	// timestamp rather than aborting.
+	Err error
+}
+
+// SequenceOptions configures Sequence.
+type SequenceOptions struct {
+	// Client fetches frames. Defaults to DefaultClient; set it to apply a rate
+	// limiter or custom retry policy, which matters here in particular since a
+	// multi-hour timelapse at a high zoom level issues a lot of requests.
+	Client *Client
+	// Level is the zoom level to fetch. If zero, DefaultLevel is used.
+	Level int
+}
+
+// Sequence yields a Frame for every step between from and to (inclusive),
+// which is useful for building timelapses; Himawari publishes a new full-disc
+// roughly every 10 minutes, so step is typically a multiple of that. Each
+// timestamp is a distinct full-disc capture, so there are no chunks to share
+// across steps; instead, each Image fetch goes through opts.Client, which
+// applies its own concurrency, rate limiting and retries. Missing timestamps
+// are skipped (Frame.Err is set) rather than aborting the whole sequence. The
+// returned channel is closed once ctx is done or every step has been produced.
+//
+// step must be positive; Sequence sends a single error Frame and returns
+// otherwise.
+func Sequence(ctx context.Context, from, to time.Time, step time.Duration, opts SequenceOptions) <-chan Frame {
+	client := opts.Client
+	if client == nil {
+		client = DefaultClient
+	}
+	level := opts.Level
+	if level <= 0 {
+		level = DefaultLevel
+	}
+	out := make(chan Frame)
+	go func() {
+		defer close(out)
+		if step <= 0 {
+			select {
+			case out <- Frame{Time: from, Err: fmt.Errorf("himawari: step must be positive, got %s", step)}:
+			case <-ctx.Done():
+			}
+			return
+		}
+		for t := from; !t.After(to); t = t.Add(step) {
+			if ctx.Err() != nil {
+				return
+			}
+			frame := Frame{Time: t}
+			if ok, err := probeChunk(ctx, client, t, level); err != nil {
+				frame.Err = err
+			} else if !ok {
+				frame.Err = fmt.Errorf("himawari: no snapshot published for %s", t.UTC())
+			} else if img, err := client.Image(ctx, t, level); err != nil {
+				frame.Err = err
+			} else {
+				frame.Image = img
+			}
+			select {
+			case out <- frame:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// probeChunk reports whether a chunk exists for t by HEAD-ing the first tile,
+// without downloading and decoding it.
+func probeChunk(ctx context.Context, client *Client, t time.Time, level int) (bool, error) {
+	if level <= 0 {
+		level = DefaultLevel
+	}
+	return client.head(ctx, ChunkUrl(t, level, Width, 0, 0))
+}
+
+// AnimFormat identifies an animation encoding for EncodeAnimation.
+type AnimFormat int
+
+const (
+	// AnimGIF encodes as an animated GIF (the default).
+	AnimGIF AnimFormat = iota
+	// AnimAPNG encodes as an animated PNG, via APNGEncoder.
+	AnimAPNG
+	// AnimMP4 encodes as MP4, via FFmpegEncoder.
+	AnimMP4
+)
+
+// AnimOptions configures EncodeAnimation.
+type AnimOptions struct {
+	Format AnimFormat
+	// Delay is the duration each frame is shown for. Defaults to 500ms.
+	Delay time.Duration
+}
+
+// APNGEncoder encodes frames as an animated PNG to w. himawari has no
+// built-in APNG encoder (the standard library doesn't have one), so callers
+// that want AnimAPNG must set this.
+var APNGEncoder func(w io.Writer, frames []image.Image, delay time.Duration) error
+
+// FFmpegEncoder encodes frames as MP4 to w, played back at the given frames
+// per second. himawari has no built-in MP4 encoder, so callers that want
+// AnimMP4 must set this, e.g. to a wrapper that pipes frames into an ffmpeg
+// subprocess.
+var FFmpegEncoder func(w io.Writer, frames []image.Image, fps int) error
+
+// EncodeAnimation writes frames to w as an animation in the format described
+// by opts. Nil frames (as produced by a Frame with Err set) are skipped, so
+// the result of draining a Sequence can be passed in directly.
+func EncodeAnimation(w io.Writer, frames []image.Image, opts AnimOptions) error {
+	delay := opts.Delay
+	if delay <= 0 {
+		delay = 500 * time.Millisecond
+	}
+	nonNil := frames[:0:0]
+	for _, img := range frames {
+		if img != nil {
+			nonNil = append(nonNil, img)
+		}
+	}
+	frames = nonNil
+	switch opts.Format {
+	case AnimGIF:
+		return encodeGIF(w, frames, delay)
+	case AnimAPNG:
+		if APNGEncoder == nil {
+			return fmt.Errorf("himawari: AnimAPNG requires APNGEncoder to be set")
+		}
+		return APNGEncoder(w, frames, delay)
+	case AnimMP4:
+		if FFmpegEncoder == nil {
+			return fmt.Errorf("himawari: AnimMP4 requires FFmpegEncoder to be set")
+		}
+		fps := int(time.Second / delay)
+		if fps <= 0 {
+			fps = 1
+		}
+		return FFmpegEncoder(w, frames, fps)
+	default:
+		return fmt.Errorf("himawari: unknown animation format %v", opts.Format)
+	}
+}
+
+func encodeGIF(w io.Writer, frames []image.Image, delay time.Duration) error {
+	delayCentis := int(delay / (10 * time.Millisecond))
+	g := &gif.GIF{}
+	for _, img := range frames {
+		b := img.Bounds()
+		pal := image.NewPaletted(b, palette.Plan9)
+		draw.FloydSteinberg.Draw(pal, b, img, b.Min)
+		g.Image = append(g.Image, pal)
+		g.Delay = append(g.Delay, delayCentis)
+	}
+	return gif.EncodeAll(w, g)
+}
@@ -0,0 +1,87 @@
+package himawari
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/gif"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSequenceNonPositiveStep(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	frames := Sequence(ctx, from, from, 0, SequenceOptions{})
+
+	frame, ok := <-frames
+	if !ok {
+		t.Fatal("expected one error Frame, got closed channel")
+	}
+	if frame.Err == nil {
+		t.Fatal("expected Frame.Err to be set for a non-positive step")
+	}
+	if _, ok := <-frames; ok {
+		t.Fatal("expected channel to be closed after the error Frame")
+	}
+}
+
+func TestSequenceUsesProvidedClient(t *testing.T) {
+	body := pngChunk(t)
+	var requests int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write(body)
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(20 * time.Minute)
+	step := 10 * time.Minute
+
+	var frames []Frame
+	for f := range Sequence(ctx, from, to, step, SequenceOptions{Client: c, Level: 1}) {
+		frames = append(frames, f)
+	}
+
+	if got, want := len(frames), 3; got != want {
+		t.Fatalf("got %d frames, want %d", got, want)
+	}
+	for i, f := range frames {
+		if f.Err != nil {
+			t.Fatalf("frame %d: unexpected error: %v", i, f.Err)
+		}
+		if f.Image == nil {
+			t.Fatalf("frame %d: expected an Image", i)
+		}
+	}
+	// One HEAD probe + one GET fetch per frame, all against the injected
+	// Client's server rather than DefaultClient's real host.
+	if got, want := atomic.LoadInt32(&requests), int32(2*len(frames)); got != want {
+		t.Fatalf("got %d requests against the provided Client, want %d", got, want)
+	}
+}
+
+func TestEncodeAnimationSkipsNilFrames(t *testing.T) {
+	solid := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	frames := []image.Image{solid, nil, solid, nil}
+
+	var buf bytes.Buffer
+	if err := EncodeAnimation(&buf, frames, AnimOptions{Format: AnimGIF}); err != nil {
+		t.Fatalf("EncodeAnimation: %v", err)
+	}
+
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("DecodeAll: %v", err)
+	}
+	if got, want := len(g.Image), 2; got != want {
+		t.Fatalf("got %d encoded frames, want %d (nil frames should be skipped)", got, want)
+	}
+}
@@ -0,0 +1,186 @@
+// Package server exposes Himawari-8 imagery over HTTP, serving individual
+// tiles and the composed full-disc image with caching and conditional GET
+// support so that CDNs and browsers can cache aggressively.
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dennwc/himawari"
+)
+
+// Fetcher abstracts the calls server needs to make against Himawari-8,
+// allowing tests (or alternative backends) to inject their own implementation.
+type Fetcher interface {
+	Latest() (time.Time, error)
+	Chunk(t time.Time, level, x, y int) (image.Image, error)
+	Image(t time.Time, level int) (image.Image, error)
+}
+
+// defaultFetcher is a Fetcher backed by the himawari package's default client.
+type defaultFetcher struct{}
+
+func (defaultFetcher) Latest() (time.Time, error) { return himawari.Latest() }
+
+func (defaultFetcher) Chunk(t time.Time, level, x, y int) (image.Image, error) {
+	return himawari.Chunk(t, level, x, y)
+}
+
+func (defaultFetcher) Image(t time.Time, level int) (image.Image, error) {
+	return himawari.Image(t, level)
+}
+
+// Options configures a Server.
+type Options struct {
+	// Fetcher is used to resolve the latest timestamp and fetch tiles/images.
+	// Defaults to the himawari package functions.
+	Fetcher Fetcher
+	// CacheWindow is how long a fetched tile or image is served without being
+	// refreshed in the background. Defaults to 10 minutes, matching the
+	// publishing interval of new Himawari-8 snapshots.
+	CacheWindow time.Duration
+}
+
+// NewServer creates an http.Handler serving:
+//
+//	/himawari/{level}/{x}/{y}.png - an individual chunk of the latest snapshot
+//	/himawari/latest.png          - the composed image at DefaultLevel
+//
+// Unlike the raw himawari.Chunk/Image calls, results are cached for
+// opts.CacheWindow: requests within the window are served from memory, and a
+// stale entry triggers a background refresh rather than blocking the caller.
+func NewServer(opts Options) *Server {
+	if opts.Fetcher == nil {
+		opts.Fetcher = defaultFetcher{}
+	}
+	if opts.CacheWindow <= 0 {
+		opts.CacheWindow = 10 * time.Minute
+	}
+	return &Server{
+		opts:  opts,
+		tiles: make(map[tileKey]*entry),
+	}
+}
+
+// Server is an http.Handler serving Himawari-8 tiles and composed images.
+type Server struct {
+	opts Options
+
+	mu    sync.RWMutex
+	tiles map[tileKey]*entry
+}
+
+type tileKey struct {
+	level, x, y int
+}
+
+// entry is a cached, already-encoded PNG for a tile or composed image.
+type entry struct {
+	t          time.Time
+	buf        []byte
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+var (
+	tileRe   = regexp.MustCompile(`^/himawari/(\d+)/(\d+)/(\d+)\.png$`)
+	latestRe = regexp.MustCompile(`^/himawari/latest\.png$`)
+)
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if m := tileRe.FindStringSubmatch(r.URL.Path); m != nil {
+		level, _ := strconv.Atoi(m[1])
+		x, _ := strconv.Atoi(m[2])
+		y, _ := strconv.Atoi(m[3])
+		s.serveTile(w, r, tileKey{level: level, x: x, y: y})
+		return
+	}
+	if latestRe.MatchString(r.URL.Path) {
+		s.serveTile(w, r, tileKey{level: himawari.DefaultLevel, x: -1, y: -1})
+		return
+	}
+	http.NotFound(w, r)
+}
+
+func (s *Server) serveTile(w http.ResponseWriter, r *http.Request, key tileKey) {
+	e, err := s.lookup(key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	etag := fmt.Sprintf(`"%d-%d-%d-%d"`, e.t.UTC().Unix(), key.level, key.x, key.y)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", e.t.UTC().Format(http.TimeFormat))
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(e.buf)
+}
+
+// lookup returns the cached entry for key, fetching it synchronously if it
+// has never been fetched, or kicking off a background refresh (and returning
+// the stale entry) if it is older than CacheWindow.
+func (s *Server) lookup(key tileKey) (*entry, error) {
+	s.mu.RLock()
+	e, ok := s.tiles[key]
+	s.mu.RUnlock()
+	if !ok {
+		return s.fetch(key)
+	}
+	if time.Since(e.fetchedAt) >= s.opts.CacheWindow {
+		s.mu.Lock()
+		if !e.refreshing {
+			e.refreshing = true
+			go s.refresh(key, e)
+		}
+		s.mu.Unlock()
+	}
+	return e, nil
+}
+
+func (s *Server) refresh(key tileKey, old *entry) {
+	defer func() {
+		s.mu.Lock()
+		old.refreshing = false
+		s.mu.Unlock()
+	}()
+	if _, err := s.fetch(key); err != nil {
+		return
+	}
+}
+
+func (s *Server) fetch(key tileKey) (*entry, error) {
+	t, err := s.opts.Fetcher.Latest()
+	if err != nil {
+		return nil, err
+	}
+	var img image.Image
+	if key.x < 0 {
+		img, err = s.opts.Fetcher.Image(t, key.level)
+	} else {
+		img, err = s.opts.Fetcher.Chunk(t, key.level, key.x, key.y)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	e := &entry{t: t, buf: buf.Bytes(), fetchedAt: time.Now()}
+	s.mu.Lock()
+	s.tiles[key] = e
+	s.mu.Unlock()
+	return e, nil
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"image"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeFetcher struct {
+	t       time.Time
+	latestN int32
+	fetchN  int32
+}
+
+func (f *fakeFetcher) Latest() (time.Time, error) {
+	atomic.AddInt32(&f.latestN, 1)
+	return f.t, nil
+}
+
+func (f *fakeFetcher) Chunk(t time.Time, level, x, y int) (image.Image, error) {
+	atomic.AddInt32(&f.fetchN, 1)
+	return image.NewRGBA(image.Rect(0, 0, 4, 4)), nil
+}
+
+func (f *fakeFetcher) Image(t time.Time, level int) (image.Image, error) {
+	atomic.AddInt32(&f.fetchN, 1)
+	return image.NewRGBA(image.Rect(0, 0, 4, 4)), nil
+}
+
+func TestServeTile(t *testing.T) {
+	f := &fakeFetcher{t: time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)}
+	s := NewServer(Options{Fetcher: f})
+
+	rr := httptest.NewRecorder()
+	s.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/himawari/4/1/2.png", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200", rr.Code)
+	}
+	if rr.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("got Content-Type %q", rr.Header().Get("Content-Type"))
+	}
+	if rr.Body.Len() == 0 {
+		t.Fatal("expected a non-empty PNG body")
+	}
+	etag := rr.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected an ETag header")
+	}
+	if atomic.LoadInt32(&f.fetchN) != 1 {
+		t.Fatalf("got %d fetches, want 1", f.fetchN)
+	}
+}
+
+func TestServeTileConditionalGet(t *testing.T) {
+	f := &fakeFetcher{t: time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)}
+	s := NewServer(Options{Fetcher: f})
+
+	rr := httptest.NewRecorder()
+	s.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/himawari/4/1/2.png", nil))
+	etag := rr.Header().Get("ETag")
+
+	req := httptest.NewRequest(http.MethodGet, "/himawari/4/1/2.png", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr2 := httptest.NewRecorder()
+	s.ServeHTTP(rr2, req)
+
+	if rr2.Code != http.StatusNotModified {
+		t.Fatalf("got status %d, want 304", rr2.Code)
+	}
+	if rr2.Body.Len() != 0 {
+		t.Fatalf("expected an empty body on 304, got %d bytes", rr2.Body.Len())
+	}
+	// The conditional request still needs a cached entry, but must not count
+	// as a second image fetch since it's inside the cache window.
+	if got := atomic.LoadInt32(&f.fetchN); got != 1 {
+		t.Fatalf("got %d fetches, want 1 (second request should be served from cache)", got)
+	}
+}
+
+func TestServeTileCachesWithinWindow(t *testing.T) {
+	f := &fakeFetcher{t: time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)}
+	s := NewServer(Options{Fetcher: f, CacheWindow: time.Hour})
+
+	for i := 0; i < 3; i++ {
+		rr := httptest.NewRecorder()
+		s.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/himawari/4/1/2.png", nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("request %d: got status %d, want 200", i, rr.Code)
+		}
+	}
+	if got := atomic.LoadInt32(&f.fetchN); got != 1 {
+		t.Fatalf("got %d fetches across 3 requests within the cache window, want 1", got)
+	}
+}
+
+func TestServeTileRefreshesWhenStale(t *testing.T) {
+	f := &fakeFetcher{t: time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)}
+	s := NewServer(Options{Fetcher: f, CacheWindow: time.Millisecond})
+
+	rr := httptest.NewRecorder()
+	s.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/himawari/4/1/2.png", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200", rr.Code)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	// This request should still be served immediately from the stale entry...
+	rr2 := httptest.NewRecorder()
+	s.ServeHTTP(rr2, httptest.NewRequest(http.MethodGet, "/himawari/4/1/2.png", nil))
+	if rr2.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200", rr2.Code)
+	}
+
+	// ...while a background refresh is kicked off; give it a moment to land.
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&f.fetchN) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := atomic.LoadInt32(&f.fetchN); got < 2 {
+		t.Fatalf("got %d fetches, want at least 2 (a background refresh of the stale entry)", got)
+	}
+}
+
+func TestServeLatestAndNotFound(t *testing.T) {
+	f := &fakeFetcher{t: time.Now()}
+	s := NewServer(Options{Fetcher: f})
+
+	rr := httptest.NewRecorder()
+	s.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/himawari/latest.png", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200", rr.Code)
+	}
+
+	rr2 := httptest.NewRecorder()
+	s.ServeHTTP(rr2, httptest.NewRequest(http.MethodGet, "/nope", nil))
+	if rr2.Code != http.StatusNotFound {
+		t.Fatalf("got status %d, want 404", rr2.Code)
+	}
+}
@@ -0,0 +1,56 @@
+package himawari
+
+import (
+	"image"
+	"image/draw"
+)
+
+// asRGBA returns img as an *image.RGBA, converting it if necessary.
+func asRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(b)
+	draw.Draw(rgba, b, img, b.Min, draw.Src)
+	return rgba
+}
+
+// transparentLuminanceThreshold is the 0-255 luminance below which a pixel
+// outside the disc mask is considered part of the black space background.
+const transparentLuminanceThreshold = 10
+
+// discRadiusRatio is the empirically observed ratio, for the D531106
+// product, between a composed canvas's side length and the radius of
+// Earth's illuminated disc centered within it.
+const discRadiusRatio = 0.47
+
+// makeTransparent clears the alpha of canvas pixels that lie outside the
+// Earth's disc and are near-black, so that only the illuminated disc keeps
+// its pixels. This is useful for compositing Himawari imagery over a custom
+// desktop background.
+func makeTransparent(canvas *image.RGBA) {
+	b := canvas.Bounds()
+	cx := float64(b.Min.X+b.Max.X) / 2
+	cy := float64(b.Min.Y+b.Max.Y) / 2
+	side := float64(b.Dx())
+	if h := float64(b.Dy()); h < side {
+		side = h
+	}
+	radius := discRadiusRatio * side
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			if dx*dx+dy*dy <= radius*radius {
+				continue
+			}
+			c := canvas.RGBAAt(x, y)
+			lum := (299*uint32(c.R) + 587*uint32(c.G) + 114*uint32(c.B)) / 1000
+			if lum < transparentLuminanceThreshold {
+				c.A = 0
+				canvas.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
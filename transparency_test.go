@@ -0,0 +1,59 @@
+package himawari
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestMakeTransparent(t *testing.T) {
+	const side = 100
+	img := image.NewRGBA(image.Rect(0, 0, side, side))
+	// Fill everything with near-black "space".
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			img.SetRGBA(x, y, color.RGBA{R: 1, G: 1, B: 1, A: 255})
+		}
+	}
+	// Paint a bright disc pixel at the center and one near the edge of the
+	// expected disc radius (discRadiusRatio * side = 47).
+	img.SetRGBA(side/2, side/2, color.RGBA{R: 200, G: 200, B: 200, A: 255})
+	img.SetRGBA(side/2+40, side/2, color.RGBA{R: 200, G: 200, B: 200, A: 255})
+
+	makeTransparent(img)
+
+	if a := img.RGBAAt(side/2, side/2).A; a != 255 {
+		t.Fatalf("bright center pixel got alpha %d, want opaque (255)", a)
+	}
+	if a := img.RGBAAt(side/2+40, side/2).A; a != 255 {
+		t.Fatalf("bright pixel within disc radius got alpha %d, want opaque (255)", a)
+	}
+	// Corner: far outside the disc and black, must become transparent.
+	if a := img.RGBAAt(0, 0).A; a != 0 {
+		t.Fatalf("black corner pixel got alpha %d, want transparent (0)", a)
+	}
+	// Near-black pixel well inside the disc radius must be left alone.
+	if a := img.RGBAAt(side/2+10, side/2).A; a != 255 {
+		t.Fatalf("near-black pixel inside disc radius got alpha %d, want opaque (255)", a)
+	}
+}
+
+func TestAsRGBA(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 2, 2))
+	src.SetGray(1, 1, color.Gray{Y: 128})
+
+	rgba := asRGBA(src)
+	if rgba.Bounds() != src.Bounds() {
+		t.Fatalf("got bounds %v, want %v", rgba.Bounds(), src.Bounds())
+	}
+	r, g, b, _ := rgba.At(1, 1).RGBA()
+	if r != g || g != b {
+		t.Fatalf("expected a gray pixel to convert to equal R/G/B, got %d/%d/%d", r, g, b)
+	}
+
+	// asRGBA must return the same *image.RGBA unchanged, not a copy.
+	already := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	if asRGBA(already) != already {
+		t.Fatal("asRGBA should return an *image.RGBA input as-is")
+	}
+}
@@ -0,0 +1,166 @@
+package himawari
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func latestJSONHandler(t *testing.T, latest time.Time, chunkBody []byte, chunkRequests *int32) http.Handler {
+	t.Helper()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "latest.json") {
+			fmt.Fprintf(w, `{"date": "%s"}`, latest.Format("2006-01-02 15:04:05"))
+			return
+		}
+		if chunkRequests != nil {
+			atomic.AddInt32(chunkRequests, 1)
+		}
+		w.Write(chunkBody)
+	})
+}
+
+func TestClientCachedImageServesFromCache(t *testing.T) {
+	body := pngChunk(t)
+	const level = 2
+	var requests int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write(body)
+	}))
+	cache := NewMemoryCache()
+	tm := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	img, err := c.CachedImage(context.Background(), cache, tm, level)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != level*Width || b.Dy() != level*Height {
+		t.Fatalf("got bounds %v, want %dx%d", b, level*Width, level*Height)
+	}
+	if got, want := atomic.LoadInt32(&requests), int32(level*level); got != want {
+		t.Fatalf("got %d chunk requests for a cold cache, want %d", got, want)
+	}
+
+	// A second call with the same cache/time/level must be served entirely
+	// from cache, issuing no new requests.
+	if _, err := c.CachedImage(context.Background(), cache, tm, level); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := atomic.LoadInt32(&requests), int32(level*level); got != want {
+		t.Fatalf("got %d requests after a fully-cached call, want %d (no new fetches)", got, want)
+	}
+}
+
+func TestClientCachedImageFetchesOnlyMissingTiles(t *testing.T) {
+	body := pngChunk(t)
+	const level = 2
+	var requests int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write(body)
+	}))
+	cache := NewMemoryCache()
+	tm := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := cache.Put(tm, level, 0, 0, image.NewRGBA(image.Rect(0, 0, Width, Height))); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.CachedImage(context.Background(), cache, tm, level); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := atomic.LoadInt32(&requests), int32(level*level-1); got != want {
+		t.Fatalf("got %d requests, want %d (one tile was already cached)", got, want)
+	}
+}
+
+func TestWatcherPollEmitsOnceThenStaysSilent(t *testing.T) {
+	latest := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	body := pngChunk(t)
+	var chunkRequests int32
+	c := newTestClient(t, latestJSONHandler(t, latest, body, &chunkRequests))
+
+	w := &Watcher{Cache: NewMemoryCache(), Client: c}
+	out := make(chan Frame, 1)
+	ctx := context.Background()
+
+	w.poll(ctx, 1, out)
+	select {
+	case f := <-out:
+		if f.Err != nil {
+			t.Fatalf("unexpected error on first poll: %v", f.Err)
+		}
+		if f.Image == nil {
+			t.Fatal("expected an Image on the first poll")
+		}
+		if !f.Time.Equal(latest) {
+			t.Fatalf("got Time %v, want %v", f.Time, latest)
+		}
+	default:
+		t.Fatal("expected a Frame on the first poll")
+	}
+	if got := atomic.LoadInt32(&chunkRequests); got != 1 {
+		t.Fatalf("got %d chunk requests, want 1", got)
+	}
+
+	// Latest is unchanged, so a second poll must not send anything.
+	w.poll(ctx, 1, out)
+	select {
+	case f := <-out:
+		t.Fatalf("expected no Frame when Latest is unchanged, got %+v", f)
+	default:
+	}
+}
+
+func TestWatcherPollSurfacesLatestError(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	c.MaxRetries = 0
+
+	w := &Watcher{Cache: NewMemoryCache(), Client: c}
+	out := make(chan Frame, 1)
+	w.poll(context.Background(), 1, out)
+
+	select {
+	case f := <-out:
+		if f.Err == nil {
+			t.Fatal("expected Frame.Err to be set when Latest fails")
+		}
+	default:
+		t.Fatal("expected an error Frame, got none")
+	}
+}
+
+func TestWatcherPollSurfacesImageError(t *testing.T) {
+	latest := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "latest.json") {
+			fmt.Fprintf(w, `{"date": "%s"}`, latest.Format("2006-01-02 15:04:05"))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	c.MaxRetries = 0
+
+	w := &Watcher{Cache: NewMemoryCache(), Client: c}
+	out := make(chan Frame, 1)
+	w.poll(context.Background(), 1, out)
+
+	select {
+	case f := <-out:
+		if f.Err == nil {
+			t.Fatal("expected Frame.Err to be set when the image fetch fails")
+		}
+		if !f.Time.Equal(latest) {
+			t.Fatalf("got Time %v, want %v", f.Time, latest)
+		}
+	default:
+		t.Fatal("expected an error Frame, got none")
+	}
+}